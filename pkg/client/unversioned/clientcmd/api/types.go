@@ -0,0 +1,105 @@
+package api
+
+// Config holds the information needed to build connect to remote kubernetes clusters as a given user
+type Config struct {
+	// Clusters is a map of referencable names to cluster configs
+	Clusters map[string]*Cluster
+	// AuthInfos is a map of referencable names to user configs
+	AuthInfos map[string]*AuthInfo
+	// Contexts is a map of referencable names to context configs
+	Contexts map[string]*Context
+	// CurrentContext is the name of the context that you would like to use by default
+	CurrentContext string
+}
+
+// NewConfig returns a new Config with non-nil maps
+func NewConfig() *Config {
+	return &Config{
+		Clusters:  map[string]*Cluster{},
+		AuthInfos: map[string]*AuthInfo{},
+		Contexts:  map[string]*Context{},
+	}
+}
+
+// Cluster contains information about how to communicate with a kubernetes cluster
+type Cluster struct {
+	// Server is the address of the kubernetes cluster (https://hostname:port)
+	Server string
+	// InsecureSkipTLSVerify skips the validity check for the server's certificate
+	InsecureSkipTLSVerify bool
+	// CertificateAuthority is the path to a cert file for the certificate authority
+	CertificateAuthority string
+	// CertificateAuthorityData contains PEM-encoded certificate authority certificates
+	CertificateAuthorityData []byte
+}
+
+// Context is a tuple of references to a cluster (how do I communicate with a kubernetes cluster), a user (how do
+// I identify myself), and a namespace (what subset of resources do I want to work with)
+type Context struct {
+	// Cluster is the name of the cluster for this context
+	Cluster string
+	// AuthInfo is the name of the authInfo for this context
+	AuthInfo string
+	// Namespace is the default namespace to use on unspecified requests
+	Namespace string
+}
+
+// AuthInfo contains information that describes identity information. This is used to tell the kubernetes cluster
+// who you are.
+type AuthInfo struct {
+	// ClientCertificate is the path to a client cert file for TLS
+	ClientCertificate string
+	// ClientCertificateData contains PEM-encoded data from a client cert file for TLS
+	ClientCertificateData []byte
+	// ClientKey is the path to a client key file for TLS
+	ClientKey string
+	// ClientKeyData contains PEM-encoded data from a client key file for TLS
+	ClientKeyData []byte
+	// Token is the bearer token for authentication to the kubernetes cluster
+	Token string
+	// Username is the username for basic authentication to the kubernetes cluster
+	Username string
+	// Password is the password for basic authentication to the kubernetes cluster
+	Password string
+	// Exec specifies a custom exec-based credential plugin to use to obtain credentials. When set, it takes
+	// precedence over the static fields above once the plugin has been invoked.
+	Exec *ExecConfig
+}
+
+// ExecConfig describes a plugin that is invoked to obtain client credentials at connection time, rather than
+// storing them statically in the kubeconfig. It mirrors the upstream client-go `users[].exec` field so that
+// credential helpers like the AWS IAM Authenticator, `gcloud`, and the Azure CLI can be used without teaching
+// this package about any particular cloud provider.
+type ExecConfig struct {
+	// Command is the name or path of the executable to run
+	Command string
+	// Args are the arguments to pass when executing the plugin
+	Args []string
+	// Env defines additional environment variables to expose to the process, beyond the inherited environment
+	Env []ExecEnvVar
+	// APIVersion is the preferred input/output version of the ExecCredential API, e.g. "client.authentication.k8s.io/v1beta1"
+	APIVersion string
+	// InstallHint is printed to the user when the plugin can't be found, to help them install it
+	InstallHint string
+	// InteractiveMode controls whether the plugin may prompt on stdin. One of ExecInteractiveModeNever,
+	// ExecInteractiveModeIfAvailable, or ExecInteractiveModeAlways. Defaults to ExecInteractiveModeIfAvailable.
+	InteractiveMode string
+}
+
+// Valid values for ExecConfig.InteractiveMode.
+const (
+	// ExecInteractiveModeNever means the plugin should never use stdin to prompt the user.
+	ExecInteractiveModeNever = "Never"
+	// ExecInteractiveModeIfAvailable means the plugin may use stdin if it is connected to a terminal; it must
+	// tolerate stdin not being available, e.g. when running non-interactively in a script or CI job.
+	ExecInteractiveModeIfAvailable = "IfAvailable"
+	// ExecInteractiveModeAlways means the plugin always uses stdin to prompt the user, and Load should fail if
+	// stdin isn't available to connect.
+	ExecInteractiveModeAlways = "Always"
+)
+
+// ExecEnvVar is used for setting environment variables when executing an exec-based credential plugin
+type ExecEnvVar struct {
+	Name  string
+	Value string
+}
@@ -0,0 +1,133 @@
+package clientcmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sync"
+	"time"
+
+	clientcmdapi "github.com/openshift/origin/pkg/client/unversioned/clientcmd/api"
+)
+
+// execCredential is the subset of the client.authentication.k8s.io ExecCredential object that we care about. A
+// plugin writes one of these as JSON to stdout in response to being invoked.
+type execCredential struct {
+	APIVersion string                `json:"apiVersion"`
+	Kind       string                `json:"kind"`
+	Status     *execCredentialStatus `json:"status,omitempty"`
+}
+
+type execCredentialStatus struct {
+	ExpirationTimestamp   *time.Time `json:"expirationTimestamp,omitempty"`
+	Token                 string     `json:"token,omitempty"`
+	ClientCertificateData string     `json:"clientCertificateData,omitempty"`
+	ClientKeyData         string     `json:"clientKeyData,omitempty"`
+}
+
+// execCredentials caches the result of running an exec plugin, keyed by "cluster/authinfo", so that repeated
+// clients built from the same loaded config don't re-invoke the plugin on every request.
+type execCredentials struct {
+	lock  sync.Mutex
+	cache map[string]*execCredentialStatus
+}
+
+var globalExecCredentials = &execCredentials{cache: map[string]*execCredentialStatus{}}
+
+// resolveExecCredential runs the exec plugin described by execConfig, unless a cached, unexpired credential is
+// already available for clusterServer+authInfoName. configDir is used to resolve a relative Command, matching the
+// same relative-path semantics ClientConfigLoadingRules.Load applies to certificate and key paths.
+func (e *execCredentials) resolveExecCredential(authInfoName, clusterServer, configDir string, execConfig *clientcmdapi.ExecConfig) (*execCredentialStatus, error) {
+	key := clusterServer + "/" + authInfoName
+
+	e.lock.Lock()
+	defer e.lock.Unlock()
+
+	if cached, ok := e.cache[key]; ok {
+		if cached.ExpirationTimestamp == nil || time.Now().Before(*cached.ExpirationTimestamp) {
+			return cached, nil
+		}
+	}
+
+	status, err := runExecPlugin(configDir, execConfig)
+	if err != nil {
+		return nil, fmt.Errorf("exec plugin %q for user %q: %v", execConfig.Command, authInfoName, err)
+	}
+
+	e.cache[key] = status
+	return status, nil
+}
+
+// runExecPlugin resolves execConfig.Command relative to configDir if necessary, runs it, and parses its stdout as
+// an ExecCredential object.
+func runExecPlugin(configDir string, execConfig *clientcmdapi.ExecConfig) (*execCredentialStatus, error) {
+	command := execConfig.Command
+	if !filepath.IsAbs(command) && configDir != "" {
+		command = filepath.Join(configDir, command)
+	}
+
+	cmd := exec.Command(command, execConfig.Args...)
+	cmd.Env = os.Environ()
+	for _, e := range execConfig.Env {
+		cmd.Env = append(cmd.Env, e.Name+"="+e.Value)
+	}
+
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	switch execConfig.InteractiveMode {
+	case clientcmdapi.ExecInteractiveModeAlways:
+		if !stdinIsAvailable() {
+			return nil, fmt.Errorf("exec plugin requires interactive mode Always, but stdin is not connected to a terminal")
+		}
+		cmd.Stdin = os.Stdin
+		cmd.Stderr = io.MultiWriter(&stderr, os.Stderr)
+	case clientcmdapi.ExecInteractiveModeNever:
+		// leave cmd.Stdin nil: the plugin gets no stdin at all, and any interactive prompt it writes to
+		// stderr only ends up in the error we return, not on the user's terminal
+	default: // "", ExecInteractiveModeIfAvailable, or an unrecognized value all default to IfAvailable
+		if stdinIsAvailable() {
+			cmd.Stdin = os.Stdin
+			cmd.Stderr = io.MultiWriter(&stderr, os.Stderr)
+		}
+	}
+
+	if err := cmd.Run(); err != nil {
+		if _, notFound := err.(*exec.Error); (notFound || os.IsNotExist(err)) && len(execConfig.InstallHint) > 0 {
+			return nil, fmt.Errorf("failed to execute: %v\n%s", err, execConfig.InstallHint)
+		}
+		return nil, fmt.Errorf("failed to execute: %v: stderr: %s", err, stderr.String())
+	}
+
+	cred := &execCredential{}
+	if err := json.Unmarshal(stdout.Bytes(), cred); err != nil {
+		return nil, fmt.Errorf("failed to parse output: %v: stderr: %s", err, stderr.String())
+	}
+	if cred.Status == nil {
+		return nil, fmt.Errorf("plugin returned no status: stderr: %s", stderr.String())
+	}
+
+	return cred.Status, nil
+}
+
+// stdinIsAvailable reports whether os.Stdin is connected to something a plugin could usefully prompt on, as
+// opposed to /dev/null or a pipe fed by a script.
+func stdinIsAvailable() bool {
+	info, err := os.Stdin.Stat()
+	if err != nil {
+		return false
+	}
+	if info.Mode()&os.ModeCharDevice == 0 {
+		return false
+	}
+	// /dev/null is a character device too, but nothing can be read from it
+	if devNull, err := os.Stat(os.DevNull); err == nil && os.SameFile(info, devNull) {
+		return false
+	}
+	return true
+}
@@ -0,0 +1,75 @@
+package clientcmd
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	clientcmdapi "github.com/openshift/origin/pkg/client/unversioned/clientcmd/api"
+)
+
+func TestRunExecPluginDefaultsToIfAvailable(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses a shell script credential plugin")
+	}
+
+	// a plugin that only succeeds if it was given a stdin exactly when one is available, matching
+	// ExecInteractiveModeIfAvailable; the test process' own stdin tells us which outcome to expect.
+	script := `#!/bin/sh
+if [ -t 0 ]; then
+  echo '{"apiVersion":"client.authentication.k8s.io/v1beta1","kind":"ExecCredential","status":{"token":"interactive-token"}}'
+else
+  echo '{"apiVersion":"client.authentication.k8s.io/v1beta1","kind":"ExecCredential","status":{"token":"non-interactive-token"}}'
+fi
+`
+	pluginPath, cleanup := writeExecScript(t, script)
+	defer cleanup()
+
+	status, err := runExecPlugin("", &clientcmdapi.ExecConfig{Command: pluginPath})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := "non-interactive-token"
+	if stdinIsAvailable() {
+		expected = "interactive-token"
+	}
+	if status.Token != expected {
+		t.Errorf("expected token %q, got %q", expected, status.Token)
+	}
+}
+
+func TestRunExecPluginAlwaysModeRequiresAvailableStdin(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses a shell script credential plugin")
+	}
+	if stdinIsAvailable() {
+		t.Skip("test process has a terminal-connected stdin; this test only exercises the unavailable case")
+	}
+
+	pluginPath, cleanup := writeExecScript(t, "#!/bin/sh\necho should-not-run\n")
+	defer cleanup()
+
+	_, err := runExecPlugin("", &clientcmdapi.ExecConfig{Command: pluginPath, InteractiveMode: clientcmdapi.ExecInteractiveModeAlways})
+	if err == nil {
+		t.Fatalf("expected an error when InteractiveMode is Always but stdin isn't available")
+	}
+}
+
+// writeExecScript writes script to a new temporary executable file and returns its path along with a func to
+// remove the temporary directory it lives in.
+func writeExecScript(t *testing.T, script string) (string, func()) {
+	t.Helper()
+	dir, err := ioutil.TempDir("", "clientcmd-exec-test")
+	if err != nil {
+		t.Fatalf("unexpected error creating tempdir: %v", err)
+	}
+
+	pluginPath := filepath.Join(dir, "plugin.sh")
+	if err := ioutil.WriteFile(pluginPath, []byte(script), 0755); err != nil {
+		t.Fatalf("unexpected error writing plugin script: %v", err)
+	}
+	return pluginPath, func() { os.RemoveAll(dir) }
+}
@@ -0,0 +1,236 @@
+package clientcmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	clientcmdapi "github.com/openshift/origin/pkg/client/unversioned/clientcmd/api"
+)
+
+// StdinPath is a sentinel CommandLinePath value meaning "read the kubeconfig from stdin". Configs loaded this way
+// have no directory to resolve relative paths (or exec plugin commands) against, and exec plugins are refused for
+// them since the plugin's provenance can't be established.
+const StdinPath = "-"
+
+// ClientConfigLoadingRules is an ExplicitPath and string slice of specific locations that are used to load
+// kubeconfig. How a key defined differently by more than one location is resolved is controlled by MergePolicy;
+// the default, FirstWins, is that the first entry found wins for any given key on conflict (see
+// TestConflictingCurrentContext), with later entries filling in anything earlier ones didn't set.
+type ClientConfigLoadingRules struct {
+	CommandLinePath      string
+	EnvVarPath           string
+	CurrentDirectoryPath string
+	HomeDirectoryPath    string
+
+	// MergePolicy controls how conflicting keys across the loaded sources are resolved. Defaults to FirstWins.
+	MergePolicy MergePolicy
+	// ConflictResolver is required when MergePolicy is Interactive, and ignored otherwise.
+	ConflictResolver ConflictResolver
+
+	// ValidateOnLoad runs Validate against the merged config before returning it, so that a buggy merged
+	// kubeconfig fails loudly at load time instead of surfacing as a cryptic API error later.
+	ValidateOnLoad bool
+}
+
+// Load reads each configured kubeconfig file, resolves any relative paths and exec plugin commands it finds
+// against the directory the defining file lived in, merges the files per MergePolicy, then resolves the current context's
+// AuthInfo to a concrete credential if it specifies an exec plugin. It discards the MergeResult that
+// LoadWithProvenance returns; callers that need provenance or Interactive resolver decisions should call
+// LoadWithProvenance directly.
+func (rules *ClientConfigLoadingRules) Load() (*clientcmdapi.Config, error) {
+	config, _, err := rules.LoadWithProvenance()
+	return config, err
+}
+
+// LoadWithProvenance does everything Load does, and additionally returns a MergeResult recording which source
+// file each cluster/user/context/current-context came from, and (for MergePolicy Interactive) the decisions the
+// ConflictResolver made. If MergePolicy is Strict and any key was defined differently by more than one source, it
+// returns a *MergeConflictError instead of a merged config.
+func (rules *ClientConfigLoadingRules) LoadWithProvenance() (*clientcmdapi.Config, *MergeResult, error) {
+	if rules.MergePolicy == Interactive && rules.ConflictResolver == nil {
+		return nil, nil, fmt.Errorf("MergePolicy is Interactive but no ConflictResolver was provided")
+	}
+
+	merged := clientcmdapi.NewConfig()
+	state := newMergeState(rules.MergePolicy, rules.ConflictResolver)
+
+	for _, path := range []string{rules.CommandLinePath, rules.EnvVarPath, rules.CurrentDirectoryPath, rules.HomeDirectoryPath} {
+		if len(path) == 0 {
+			continue
+		}
+
+		config, err := loadFromFile(path)
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+
+		configDir := ""
+		if path != StdinPath {
+			if abs, err := filepath.Abs(filepath.Dir(path)); err == nil {
+				configDir = abs
+			}
+		}
+		resolveLocalPaths(config, configDir)
+
+		mergeConfig(merged, config, path, state)
+	}
+
+	result, err := state.finish()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if err := resolveSelectedAuthInfoExec(merged, result.Provenance); err != nil {
+		return nil, nil, err
+	}
+
+	if rules.ValidateOnLoad {
+		if validationErrs := Validate(merged); len(validationErrs) > 0 {
+			return nil, nil, &ValidationFailedError{Errors: validationErrs}
+		}
+	}
+
+	return merged, result, nil
+}
+
+func loadFromFile(path string) (*clientcmdapi.Config, error) {
+	var data []byte
+	var err error
+	if path == StdinPath {
+		data, err = ioutil.ReadAll(os.Stdin)
+	} else {
+		data, err = ioutil.ReadFile(path)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	config := clientcmdapi.NewConfig()
+	if err := json.Unmarshal(data, config); err != nil {
+		return nil, fmt.Errorf("couldn't load config from %q: %v", path, err)
+	}
+	return config, nil
+}
+
+// resolveLocalPaths makes CertificateAuthority, ClientCertificate, and ClientKey paths (and a non-absolute exec
+// Command) absolute by resolving them against configDir, the directory the kubeconfig that defined them lived in.
+func resolveLocalPaths(config *clientcmdapi.Config, configDir string) {
+	if len(configDir) == 0 {
+		return
+	}
+
+	for _, cluster := range config.Clusters {
+		resolvePath(&cluster.CertificateAuthority, configDir)
+	}
+	for _, authInfo := range config.AuthInfos {
+		resolvePath(&authInfo.ClientCertificate, configDir)
+		resolvePath(&authInfo.ClientKey, configDir)
+		if authInfo.Exec != nil {
+			resolvePath(&authInfo.Exec.Command, configDir)
+		}
+	}
+}
+
+func resolvePath(path *string, configDir string) {
+	if len(*path) == 0 || filepath.IsAbs(*path) {
+		return
+	}
+	*path = filepath.Join(configDir, *path)
+}
+
+// resolveSelectedAuthInfoExec invokes the exec plugin for the AuthInfo backing config.CurrentContext, if any, and
+// overwrites that AuthInfo's static credential fields with the plugin's result so that downstream REST config
+// construction never needs to know exec plugins exist. Unlike path resolution, this intentionally does not touch
+// every AuthInfo in the merged config: a kubeconfig can reasonably hold exec-based entries for clusters nobody is
+// talking to right now (EKS, GKE, AKS, ...), and shelling out to all of their credential helpers on every Load
+// would be both slow and a correctness hazard, since a single missing/broken unrelated helper would fail the
+// whole load. This mirrors how upstream client-go only resolves the exec plugin for the auth info actually backing
+// the REST config being built. provenance is the per-key source-path map LoadWithProvenance produced for the
+// merged config; it's consulted to refuse a plugin whose AuthInfo came from a stdin-sourced kubeconfig, since
+// there's no directory to safely vouch for a relative Command against.
+func resolveSelectedAuthInfoExec(config *clientcmdapi.Config, provenance map[string]string) error {
+	if len(config.CurrentContext) == 0 {
+		return nil
+	}
+	context, ok := config.Contexts[config.CurrentContext]
+	if !ok {
+		return nil
+	}
+	authInfo, ok := config.AuthInfos[context.AuthInfo]
+	if !ok || authInfo.Exec == nil {
+		return nil
+	}
+
+	if provenance["authinfos/"+context.AuthInfo] == StdinPath {
+		return fmt.Errorf("user %q specifies an exec provider, but exec plugins are not allowed when the kubeconfig is read from stdin", context.AuthInfo)
+	}
+
+	server := ""
+	if cluster, ok := config.Clusters[context.Cluster]; ok {
+		server = cluster.Server
+	}
+	status, err := globalExecCredentials.resolveExecCredential(context.AuthInfo, server, "", authInfo.Exec)
+	if err != nil {
+		return err
+	}
+
+	authInfo.Token = status.Token
+	if len(status.ClientCertificateData) > 0 {
+		authInfo.ClientCertificateData = []byte(status.ClientCertificateData)
+	}
+	if len(status.ClientKeyData) > 0 {
+		authInfo.ClientKeyData = []byte(status.ClientKeyData)
+	}
+
+	return nil
+}
+
+// mergeConfig folds src, which was loaded from sourcePath, into dst, consulting state.policy/state.resolver for
+// any key dst already has a (different) value for, and recording provenance for every key it sets.
+func mergeConfig(dst, src *clientcmdapi.Config, sourcePath string, state *mergeState) {
+	for name, obj := range src.Clusters {
+		key := "clusters/" + name
+		var existing *clientcmdapi.Cluster
+		if dst.Clusters[name] != nil {
+			existing = dst.Clusters[name]
+		}
+		if state.resolve(key, sourcePath, existing, obj) {
+			dst.Clusters[name] = obj
+			state.recordProvenance(key, sourcePath)
+		}
+	}
+	for name, obj := range src.AuthInfos {
+		key := "authinfos/" + name
+		var existing *clientcmdapi.AuthInfo
+		if dst.AuthInfos[name] != nil {
+			existing = dst.AuthInfos[name]
+		}
+		if state.resolve(key, sourcePath, existing, obj) {
+			dst.AuthInfos[name] = obj
+			state.recordProvenance(key, sourcePath)
+		}
+	}
+	for name, obj := range src.Contexts {
+		key := "contexts/" + name
+		var existing *clientcmdapi.Context
+		if dst.Contexts[name] != nil {
+			existing = dst.Contexts[name]
+		}
+		if state.resolve(key, sourcePath, existing, obj) {
+			dst.Contexts[name] = obj
+			state.recordProvenance(key, sourcePath)
+		}
+	}
+	if len(src.CurrentContext) > 0 {
+		if state.resolve("currentContext", sourcePath, dst.CurrentContext, src.CurrentContext) {
+			dst.CurrentContext = src.CurrentContext
+			state.recordProvenance("currentContext", sourcePath)
+		}
+	}
+}
@@ -0,0 +1,229 @@
+package clientcmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	clientcmdapi "github.com/openshift/origin/pkg/client/unversioned/clientcmd/api"
+)
+
+func writeConfig(t *testing.T, config *clientcmdapi.Config) string {
+	data, err := json.Marshal(config)
+	if err != nil {
+		t.Fatalf("unexpected error marshalling config: %v", err)
+	}
+
+	f, err := ioutil.TempFile("", "clientcmd-test")
+	if err != nil {
+		t.Fatalf("unexpected error creating tempfile: %v", err)
+	}
+	defer f.Close()
+	if _, err := f.Write(data); err != nil {
+		t.Fatalf("unexpected error writing tempfile: %v", err)
+	}
+
+	return f.Name()
+}
+
+func TestConflictingCurrentContext(t *testing.T) {
+	commandLineFile := writeConfig(t, &clientcmdapi.Config{CurrentContext: "any-context-value"})
+	defer os.Remove(commandLineFile)
+	envVarFile := writeConfig(t, &clientcmdapi.Config{CurrentContext: "a-different-context"})
+	defer os.Remove(envVarFile)
+
+	rules := ClientConfigLoadingRules{
+		CommandLinePath: commandLineFile,
+		EnvVarPath:      envVarFile,
+	}
+
+	merged, err := rules.Load()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if merged.CurrentContext != "any-context-value" {
+		t.Errorf("expected first-seen context to win, got %v", merged.CurrentContext)
+	}
+}
+
+func TestResolveRelativePaths(t *testing.T) {
+	config := &clientcmdapi.Config{
+		AuthInfos: map[string]*clientcmdapi.AuthInfo{
+			"relative-user": {ClientCertificate: "relative/client/cert", ClientKey: "../relative/client/key"},
+			"absolute-user": {ClientCertificate: "/absolute/client/cert", ClientKey: "/absolute/client/key"},
+		},
+		Clusters: map[string]*clientcmdapi.Cluster{
+			"relative-server": {CertificateAuthority: "../relative/ca"},
+			"absolute-server": {CertificateAuthority: "/absolute/ca"},
+		},
+	}
+
+	configFile := writeConfig(t, config)
+	defer os.Remove(configFile)
+	configDir, err := filepath.Abs(filepath.Dir(configFile))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	rules := ClientConfigLoadingRules{CommandLinePath: configFile}
+	merged, err := rules.Load()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if expected := filepath.Join(configDir, "relative/client/cert"); merged.AuthInfos["relative-user"].ClientCertificate != expected {
+		t.Errorf("expected %v, got %v", expected, merged.AuthInfos["relative-user"].ClientCertificate)
+	}
+	if expected := "/absolute/client/cert"; merged.AuthInfos["absolute-user"].ClientCertificate != expected {
+		t.Errorf("expected %v, got %v", expected, merged.AuthInfos["absolute-user"].ClientCertificate)
+	}
+	if expected := filepath.Join(configDir, "../relative/ca"); merged.Clusters["relative-server"].CertificateAuthority != expected {
+		t.Errorf("expected %v, got %v", expected, merged.Clusters["relative-server"].CertificateAuthority)
+	}
+	if expected := "/absolute/ca"; merged.Clusters["absolute-server"].CertificateAuthority != expected {
+		t.Errorf("expected %v, got %v", expected, merged.Clusters["absolute-server"].CertificateAuthority)
+	}
+}
+
+func TestResolveExecCommandRelativePath(t *testing.T) {
+	config := &clientcmdapi.Config{
+		AuthInfos: map[string]*clientcmdapi.AuthInfo{
+			"exec-user": {Exec: &clientcmdapi.ExecConfig{Command: "./bin/authhelper"}},
+		},
+	}
+
+	configFile := writeConfig(t, config)
+	defer os.Remove(configFile)
+	configDir, err := filepath.Abs(filepath.Dir(configFile))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	resolveLocalPaths(config, configDir)
+
+	if expected := filepath.Join(configDir, "./bin/authhelper"); config.AuthInfos["exec-user"].Exec.Command != expected {
+		t.Errorf("expected %v, got %v", expected, config.AuthInfos["exec-user"].Exec.Command)
+	}
+}
+
+func TestExecPluginRefusedFromStdin(t *testing.T) {
+	config := &clientcmdapi.Config{
+		AuthInfos: map[string]*clientcmdapi.AuthInfo{
+			"exec-user": {Exec: &clientcmdapi.ExecConfig{Command: "authhelper"}},
+		},
+		Contexts: map[string]*clientcmdapi.Context{
+			"exec-context": {AuthInfo: "exec-user"},
+		},
+		CurrentContext: "exec-context",
+	}
+
+	err := resolveSelectedAuthInfoExec(config, map[string]string{"authinfos/exec-user": StdinPath})
+	if err == nil {
+		t.Fatalf("expected an error refusing to run an exec plugin from a stdin-sourced config")
+	}
+}
+
+func writeExecPluginScript(t *testing.T, dir, token string) string {
+	t.Helper()
+	script := fmt.Sprintf(`#!/bin/sh
+echo '{"apiVersion":"client.authentication.k8s.io/v1beta1","kind":"ExecCredential","status":{"token":"%s","expirationTimestamp":"2999-01-01T00:00:00Z"}}'
+`, token)
+	pluginPath := filepath.Join(dir, token+".sh")
+	if err := ioutil.WriteFile(pluginPath, []byte(script), 0755); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return pluginPath
+}
+
+func TestExecPluginInvocationAndCaching(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses a shell script credential plugin")
+	}
+
+	dir, err := ioutil.TempDir("", "clientcmd-exec-test")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	pluginPath := writeExecPluginScript(t, dir, "plugin-token")
+
+	config := &clientcmdapi.Config{
+		AuthInfos: map[string]*clientcmdapi.AuthInfo{
+			"exec-user": {Exec: &clientcmdapi.ExecConfig{Command: pluginPath}},
+		},
+		Clusters: map[string]*clientcmdapi.Cluster{
+			"exec-cluster": {Server: "https://example.com"},
+		},
+		Contexts: map[string]*clientcmdapi.Context{
+			"exec-context": {AuthInfo: "exec-user", Cluster: "exec-cluster"},
+		},
+		CurrentContext: "exec-context",
+	}
+	provenance := map[string]string{"authinfos/exec-user": pluginPath}
+
+	if err := resolveSelectedAuthInfoExec(config, provenance); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if config.AuthInfos["exec-user"].Token != "plugin-token" {
+		t.Errorf("expected exec plugin token to be injected, got %q", config.AuthInfos["exec-user"].Token)
+	}
+
+	// remove the plugin and confirm the cached credential is reused instead of re-invoking it
+	if err := os.Remove(pluginPath); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := resolveSelectedAuthInfoExec(config, provenance); err != nil {
+		t.Errorf("expected cached credential to be reused without re-invoking the missing plugin: %v", err)
+	}
+}
+
+func TestExecPluginNotInvokedForUnselectedContext(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses a shell script credential plugin")
+	}
+
+	dir, err := ioutil.TempDir("", "clientcmd-exec-test")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	// this plugin doesn't exist on disk at all: resolving it would fail, proving the AuthInfo for the
+	// non-current context was never invoked.
+	missingPluginPath := filepath.Join(dir, "does-not-exist.sh")
+
+	config := &clientcmdapi.Config{
+		AuthInfos: map[string]*clientcmdapi.AuthInfo{
+			"selected-user":   {Exec: &clientcmdapi.ExecConfig{Command: writeExecPluginScript(t, dir, "selected-token")}},
+			"unselected-user": {Exec: &clientcmdapi.ExecConfig{Command: missingPluginPath}},
+		},
+		Clusters: map[string]*clientcmdapi.Cluster{
+			"selected-cluster":   {Server: "https://selected.example.com"},
+			"unselected-cluster": {Server: "https://unselected.example.com"},
+		},
+		Contexts: map[string]*clientcmdapi.Context{
+			"selected-context":   {AuthInfo: "selected-user", Cluster: "selected-cluster"},
+			"unselected-context": {AuthInfo: "unselected-user", Cluster: "unselected-cluster"},
+		},
+		CurrentContext: "selected-context",
+	}
+	provenance := map[string]string{
+		"authinfos/selected-user":   filepath.Join(dir, "selected-token.sh"),
+		"authinfos/unselected-user": missingPluginPath,
+	}
+
+	if err := resolveSelectedAuthInfoExec(config, provenance); err != nil {
+		t.Fatalf("unexpected error resolving the current context's exec plugin: %v", err)
+	}
+	if config.AuthInfos["selected-user"].Token != "selected-token" {
+		t.Errorf("expected the selected context's exec plugin token to be injected, got %q", config.AuthInfos["selected-user"].Token)
+	}
+	if config.AuthInfos["unselected-user"].Token != "" {
+		t.Errorf("expected the unselected context's exec plugin to never be invoked, got token %q", config.AuthInfos["unselected-user"].Token)
+	}
+}
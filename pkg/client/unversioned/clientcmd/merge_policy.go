@@ -0,0 +1,134 @@
+package clientcmd
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// MergePolicy controls what ClientConfigLoadingRules.Load does when the same named cluster, user, context, or the
+// current-context is defined differently by more than one of the loaded kubeconfig files.
+type MergePolicy string
+
+const (
+	// FirstWins keeps whichever source defined the key first, the historical behavior of this package (see
+	// TestConflictingCurrentContext). It is the default when MergePolicy is unset.
+	FirstWins MergePolicy = "FirstWins"
+	// LastWins keeps whichever source defined the key last.
+	LastWins MergePolicy = "LastWins"
+	// Strict causes Load to return a *MergeConflictError listing every colliding key instead of a merged config.
+	Strict MergePolicy = "Strict"
+	// Interactive invokes ConflictResolver for each conflict and uses whichever value it picks.
+	Interactive MergePolicy = "Interactive"
+)
+
+// ConflictResolver decides, for a single colliding key, whether the newly-seen value should replace the one
+// already chosen. existingPath and newPath are the kubeconfig files the two values came from.
+type ConflictResolver func(key string, existingPath, newPath string, existingValue, newValue interface{}) (useNew bool)
+
+// MergeConflict describes every source that defined a given key differently. Paths is in load order, so Paths[0]
+// is whichever source FirstWins would have picked.
+type MergeConflict struct {
+	Key   string
+	Paths []string
+}
+
+// MergeConflictError is returned by Load when MergePolicy is Strict and any cluster, user, context, or the
+// current-context was defined differently by more than one source.
+type MergeConflictError struct {
+	Conflicts []MergeConflict
+}
+
+func (e *MergeConflictError) Error() string {
+	lines := make([]string, 0, len(e.Conflicts))
+	for _, conflict := range e.Conflicts {
+		lines = append(lines, fmt.Sprintf("%s: conflicting values from %s", conflict.Key, strings.Join(conflict.Paths, ", ")))
+	}
+	return fmt.Sprintf("kubeconfig merge conflicts:\n%s", strings.Join(lines, "\n"))
+}
+
+// ResolverDecision records what a ConflictResolver decided for one conflicting key, for callers that want to show
+// the user (or log) how an Interactive merge was resolved.
+type ResolverDecision struct {
+	Key        string
+	SourcePath string
+	UsedNew    bool
+}
+
+// MergeResult carries the information a merge produces beyond the merged Config itself: where every key's
+// winning value came from, and, for Interactive merges, the decisions the ConflictResolver made. Provenance keys
+// are of the form "<section>/<name>", e.g. "clusters/cow-cluster", plus the bare key "currentContext".
+type MergeResult struct {
+	Provenance map[string]string
+	Decisions  []ResolverDecision
+}
+
+// mergeState accumulates provenance, conflicts, and resolver decisions across every source file Load reads, in
+// load order.
+type mergeState struct {
+	policy    MergePolicy
+	resolver  ConflictResolver
+	result    *MergeResult
+	conflicts []MergeConflict
+}
+
+func newMergeState(policy MergePolicy, resolver ConflictResolver) *mergeState {
+	if len(policy) == 0 {
+		policy = FirstWins
+	}
+	return &mergeState{
+		policy:   policy,
+		resolver: resolver,
+		result:   &MergeResult{Provenance: map[string]string{}},
+	}
+}
+
+// resolve is called for every key seen in every source. existing/newValue may be nil to mean "not yet set"/"being
+// set for the first time". It returns whether the new value should replace the current one.
+func (s *mergeState) resolve(key, newPath string, existingValue, newValue interface{}) bool {
+	existingPath, alreadySet := s.result.Provenance[key]
+	if !alreadySet {
+		return true
+	}
+	if reflect.DeepEqual(existingValue, newValue) {
+		return false
+	}
+
+	recorded := false
+	for i := range s.conflicts {
+		if s.conflicts[i].Key == key {
+			s.conflicts[i].Paths = append(s.conflicts[i].Paths, newPath)
+			recorded = true
+			break
+		}
+	}
+	if !recorded {
+		s.conflicts = append(s.conflicts, MergeConflict{Key: key, Paths: []string{existingPath, newPath}})
+	}
+
+	switch s.policy {
+	case LastWins:
+		return true
+	case Interactive:
+		useNew := s.resolver(key, existingPath, newPath, existingValue, newValue)
+		s.result.Decisions = append(s.result.Decisions, ResolverDecision{Key: key, SourcePath: newPath, UsedNew: useNew})
+		return useNew
+	case Strict:
+		return false
+	case FirstWins:
+		fallthrough
+	default:
+		return false
+	}
+}
+
+func (s *mergeState) recordProvenance(key, path string) {
+	s.result.Provenance[key] = path
+}
+
+func (s *mergeState) finish() (*MergeResult, error) {
+	if s.policy == Strict && len(s.conflicts) > 0 {
+		return nil, &MergeConflictError{Conflicts: s.conflicts}
+	}
+	return s.result, nil
+}
@@ -0,0 +1,101 @@
+package clientcmd
+
+import (
+	"os"
+	"testing"
+
+	clientcmdapi "github.com/openshift/origin/pkg/client/unversioned/clientcmd/api"
+)
+
+func TestMergePolicyLastWins(t *testing.T) {
+	commandLineFile := writeConfig(t, &clientcmdapi.Config{CurrentContext: "first-context"})
+	defer os.Remove(commandLineFile)
+	envVarFile := writeConfig(t, &clientcmdapi.Config{CurrentContext: "second-context"})
+	defer os.Remove(envVarFile)
+
+	rules := ClientConfigLoadingRules{
+		CommandLinePath: commandLineFile,
+		EnvVarPath:      envVarFile,
+		MergePolicy:     LastWins,
+	}
+
+	merged, result, err := rules.LoadWithProvenance()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if merged.CurrentContext != "second-context" {
+		t.Errorf("expected the later source to win, got %v", merged.CurrentContext)
+	}
+	if result.Provenance["currentContext"] != envVarFile {
+		t.Errorf("expected provenance to point at %v, got %v", envVarFile, result.Provenance["currentContext"])
+	}
+}
+
+func TestMergePolicyStrictReturnsConflictError(t *testing.T) {
+	commandLineFile := writeConfig(t, &clientcmdapi.Config{CurrentContext: "first-context"})
+	defer os.Remove(commandLineFile)
+	envVarFile := writeConfig(t, &clientcmdapi.Config{CurrentContext: "second-context"})
+	defer os.Remove(envVarFile)
+
+	rules := ClientConfigLoadingRules{
+		CommandLinePath: commandLineFile,
+		EnvVarPath:      envVarFile,
+		MergePolicy:     Strict,
+	}
+
+	_, _, err := rules.LoadWithProvenance()
+	conflictErr, ok := err.(*MergeConflictError)
+	if !ok {
+		t.Fatalf("expected a *MergeConflictError, got %v", err)
+	}
+	if len(conflictErr.Conflicts) != 1 || conflictErr.Conflicts[0].Key != "currentContext" {
+		t.Errorf("expected a single currentContext conflict, got %v", conflictErr.Conflicts)
+	}
+}
+
+func TestMergePolicyInteractiveRequiresResolver(t *testing.T) {
+	commandLineFile := writeConfig(t, &clientcmdapi.Config{CurrentContext: "first-context"})
+	defer os.Remove(commandLineFile)
+
+	rules := ClientConfigLoadingRules{
+		CommandLinePath: commandLineFile,
+		MergePolicy:     Interactive,
+	}
+
+	_, _, err := rules.LoadWithProvenance()
+	if err == nil {
+		t.Fatalf("expected an error when MergePolicy is Interactive with no ConflictResolver")
+	}
+}
+
+func TestMergePolicyInteractive(t *testing.T) {
+	commandLineFile := writeConfig(t, &clientcmdapi.Config{CurrentContext: "first-context"})
+	defer os.Remove(commandLineFile)
+	envVarFile := writeConfig(t, &clientcmdapi.Config{CurrentContext: "second-context"})
+	defer os.Remove(envVarFile)
+
+	resolverCalled := false
+	rules := ClientConfigLoadingRules{
+		CommandLinePath: commandLineFile,
+		EnvVarPath:      envVarFile,
+		MergePolicy:     Interactive,
+		ConflictResolver: func(key, existingPath, newPath string, existingValue, newValue interface{}) bool {
+			resolverCalled = true
+			return true // always prefer the new value
+		},
+	}
+
+	merged, result, err := rules.LoadWithProvenance()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !resolverCalled {
+		t.Errorf("expected the ConflictResolver to be invoked")
+	}
+	if merged.CurrentContext != "second-context" {
+		t.Errorf("expected the resolver's choice to win, got %v", merged.CurrentContext)
+	}
+	if len(result.Decisions) != 1 || !result.Decisions[0].UsedNew {
+		t.Errorf("expected a recorded decision favoring the new value, got %v", result.Decisions)
+	}
+}
@@ -0,0 +1,159 @@
+package clientcmd
+
+import (
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+	"time"
+
+	clientcmdapi "github.com/openshift/origin/pkg/client/unversioned/clientcmd/api"
+)
+
+// ConfigValidationError describes one problem found by Validate. Context is the context name the problem was
+// found through, if any; it's empty for problems that aren't context-specific (e.g. an unreadable cluster
+// certificate-authority file).
+type ConfigValidationError struct {
+	Context string
+	Message string
+}
+
+func (e ConfigValidationError) Error() string {
+	if len(e.Context) == 0 {
+		return e.Message
+	}
+	return fmt.Sprintf("context %q: %s", e.Context, e.Message)
+}
+
+// ValidationFailedError is returned by LoadWithProvenance when ValidateOnLoad is set and Validate finds at least
+// one problem with the merged config.
+type ValidationFailedError struct {
+	Errors []ConfigValidationError
+}
+
+func (e *ValidationFailedError) Error() string {
+	lines := make([]string, 0, len(e.Errors))
+	for _, err := range e.Errors {
+		lines = append(lines, err.Error())
+	}
+	return fmt.Sprintf("invalid kubeconfig:\n%s", strings.Join(lines, "\n"))
+}
+
+// Validate checks cfg for the kinds of problems that would otherwise surface later as a cryptic API error: a
+// context pointing at a cluster or user that doesn't exist, an auth/TLS file path that can't be read, mutually
+// exclusive auth fields set together, unparseable certificate-authority data, and expired embedded client certs.
+// It returns one ConfigValidationError per problem found, in no particular order, and a nil slice if cfg is
+// clean.
+func Validate(cfg *clientcmdapi.Config) []ConfigValidationError {
+	var errs []ConfigValidationError
+
+	for name, context := range cfg.Contexts {
+		if _, ok := cfg.Clusters[context.Cluster]; !ok {
+			errs = append(errs, ConfigValidationError{Context: name, Message: fmt.Sprintf("references cluster %q which is not defined", context.Cluster)})
+		}
+		if _, ok := cfg.AuthInfos[context.AuthInfo]; !ok {
+			errs = append(errs, ConfigValidationError{Context: name, Message: fmt.Sprintf("references user %q which is not defined", context.AuthInfo)})
+		}
+	}
+
+	if len(cfg.CurrentContext) > 0 {
+		if _, ok := cfg.Contexts[cfg.CurrentContext]; !ok {
+			errs = append(errs, ConfigValidationError{Message: fmt.Sprintf("current-context %q is not defined", cfg.CurrentContext)})
+		}
+	}
+
+	for name, cluster := range cfg.Clusters {
+		errs = append(errs, validateClusterTLS(name, cluster)...)
+	}
+
+	for name, authInfo := range cfg.AuthInfos {
+		errs = append(errs, validateAuthInfo(name, authInfo)...)
+	}
+
+	return errs
+}
+
+func validateClusterTLS(name string, cluster *clientcmdapi.Cluster) []ConfigValidationError {
+	var errs []ConfigValidationError
+
+	caData := cluster.CertificateAuthorityData
+	if len(caData) == 0 && len(cluster.CertificateAuthority) > 0 {
+		data, err := readFileIfReachable(cluster.CertificateAuthority)
+		if err != nil {
+			errs = append(errs, ConfigValidationError{Message: fmt.Sprintf("cluster %q: certificate-authority %q: %v", name, cluster.CertificateAuthority, err)})
+			return errs
+		}
+		caData = data
+	}
+	if len(caData) > 0 {
+		if _, err := parseCertificate(caData); err != nil {
+			errs = append(errs, ConfigValidationError{Message: fmt.Sprintf("cluster %q: certificate-authority does not contain a valid certificate: %v", name, err)})
+		}
+	}
+
+	return errs
+}
+
+func validateAuthInfo(name string, authInfo *clientcmdapi.AuthInfo) []ConfigValidationError {
+	var errs []ConfigValidationError
+
+	authMethods := 0
+	if len(authInfo.Token) > 0 {
+		authMethods++
+	}
+	if len(authInfo.ClientCertificate) > 0 || len(authInfo.ClientCertificateData) > 0 {
+		authMethods++
+	}
+	if authInfo.Exec != nil {
+		authMethods++
+	}
+	if len(authInfo.Username) > 0 || len(authInfo.Password) > 0 {
+		authMethods++
+	}
+	if authMethods > 1 {
+		errs = append(errs, ConfigValidationError{Message: fmt.Sprintf("user %q: token, client-certificate, exec, and username/password are mutually exclusive", name)})
+	}
+
+	certData := authInfo.ClientCertificateData
+	if len(certData) == 0 && len(authInfo.ClientCertificate) > 0 {
+		data, err := readFileIfReachable(authInfo.ClientCertificate)
+		if err != nil {
+			errs = append(errs, ConfigValidationError{Message: fmt.Sprintf("user %q: client-certificate %q: %v", name, authInfo.ClientCertificate, err)})
+			return errs
+		}
+		certData = data
+	}
+	if len(certData) > 0 {
+		cert, err := parseCertificate(certData)
+		if err != nil {
+			errs = append(errs, ConfigValidationError{Message: fmt.Sprintf("user %q: client-certificate does not contain a valid certificate: %v", name, err)})
+		} else if time.Now().After(cert.NotAfter) {
+			errs = append(errs, ConfigValidationError{Message: fmt.Sprintf("user %q: client certificate expired at %v", name, cert.NotAfter)})
+		}
+	}
+
+	if len(authInfo.ClientKey) > 0 && len(authInfo.ClientKeyData) == 0 {
+		if _, err := readFileIfReachable(authInfo.ClientKey); err != nil {
+			errs = append(errs, ConfigValidationError{Message: fmt.Sprintf("user %q: client-key %q: %v", name, authInfo.ClientKey, err)})
+		}
+	}
+
+	return errs
+}
+
+func readFileIfReachable(path string) ([]byte, error) {
+	if _, err := os.Stat(path); err != nil {
+		return nil, err
+	}
+	return ioutil.ReadFile(path)
+}
+
+func parseCertificate(data []byte) (*x509.Certificate, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("no PEM data found")
+	}
+	return x509.ParseCertificate(block.Bytes)
+}
@@ -0,0 +1,72 @@
+package clientcmd
+
+import (
+	"os"
+	"testing"
+
+	clientcmdapi "github.com/openshift/origin/pkg/client/unversioned/clientcmd/api"
+)
+
+func TestValidateDanglingReferences(t *testing.T) {
+	cfg := &clientcmdapi.Config{
+		Contexts: map[string]*clientcmdapi.Context{
+			"broken-context": {Cluster: "missing-cluster", AuthInfo: "missing-user"},
+		},
+		CurrentContext: "missing-context",
+	}
+
+	errs := Validate(cfg)
+	if len(errs) != 3 {
+		t.Fatalf("expected 3 validation errors, got %d: %v", len(errs), errs)
+	}
+}
+
+func TestValidateMutuallyExclusiveAuthFields(t *testing.T) {
+	cfg := &clientcmdapi.Config{
+		AuthInfos: map[string]*clientcmdapi.AuthInfo{
+			"both-user": {Token: "a-token", Username: "a-user"},
+		},
+	}
+
+	errs := Validate(cfg)
+	if len(errs) != 1 {
+		t.Fatalf("expected 1 validation error, got %d: %v", len(errs), errs)
+	}
+}
+
+func TestValidateCleanConfig(t *testing.T) {
+	cfg := &clientcmdapi.Config{
+		Clusters: map[string]*clientcmdapi.Cluster{
+			"my-cluster": {Server: "https://example.com"},
+		},
+		AuthInfos: map[string]*clientcmdapi.AuthInfo{
+			"my-user": {Token: "a-token"},
+		},
+		Contexts: map[string]*clientcmdapi.Context{
+			"my-context": {Cluster: "my-cluster", AuthInfo: "my-user"},
+		},
+		CurrentContext: "my-context",
+	}
+
+	if errs := Validate(cfg); len(errs) != 0 {
+		t.Errorf("expected no validation errors, got %v", errs)
+	}
+}
+
+func TestValidateOnLoadRejectsBrokenMergedConfig(t *testing.T) {
+	commandLineFile := writeConfig(t, &clientcmdapi.Config{
+		Contexts:       map[string]*clientcmdapi.Context{"ctx": {Cluster: "nope", AuthInfo: "nope"}},
+		CurrentContext: "ctx",
+	})
+	defer os.Remove(commandLineFile)
+
+	rules := ClientConfigLoadingRules{
+		CommandLinePath: commandLineFile,
+		ValidateOnLoad:  true,
+	}
+
+	_, _, err := rules.LoadWithProvenance()
+	if _, ok := err.(*ValidationFailedError); !ok {
+		t.Fatalf("expected a *ValidationFailedError, got %v", err)
+	}
+}
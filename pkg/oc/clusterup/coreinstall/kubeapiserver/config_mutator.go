@@ -0,0 +1,181 @@
+package kubeapiserver
+
+import (
+	"io/ioutil"
+	"os"
+	"path"
+	"sort"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/strategicpatch"
+
+	"github.com/ghodss/yaml"
+	"github.com/golang/glog"
+
+	configapi "github.com/openshift/origin/pkg/cmd/server/apis/config"
+	configapilatest "github.com/openshift/origin/pkg/cmd/server/apis/config/latest"
+	"github.com/openshift/origin/pkg/oc/clusterup/coreinstall/tmpformac"
+)
+
+// ConfigMutator makes a single, self-contained change to a MasterConfig that has been decoded from an existing
+// cluster-up config directory. Mutators are composed and run by MutateMasterConfig.
+type ConfigMutator func(*configapi.MasterConfig) error
+
+// MutateMasterConfig copies the config directory at srcDir to dstDir, decodes its master-config.yaml, runs every
+// mutator against it in order, and atomically re-encodes the result back to dstDir/master-config.yaml. It
+// replaces the copy/decode/mutate/re-encode/write boilerplate that used to be duplicated by every cluster-up
+// Make*Config helper.
+func MutateMasterConfig(srcDir, dstDir string, mutators ...ConfigMutator) (string, error) {
+	glog.V(1).Infof("Copying config to local directory %s", dstDir)
+	if err := tmpformac.CopyDirectory(srcDir, dstDir); err != nil {
+		return "", err
+	}
+
+	masterConfigFilename := path.Join(dstDir, "master-config.yaml")
+	originalBytes, err := ioutil.ReadFile(masterConfigFilename)
+	if err != nil {
+		return "", err
+	}
+	configObj, err := runtime.Decode(configapilatest.Codec, originalBytes)
+	if err != nil {
+		return "", err
+	}
+	masterConfig := configObj.(*configapi.MasterConfig)
+
+	for _, mutate := range mutators {
+		if err := mutate(masterConfig); err != nil {
+			return "", err
+		}
+	}
+
+	configBytes, err := configapilatest.WriteYAML(masterConfig)
+	if err != nil {
+		return "", err
+	}
+	if err := writeFileAtomically(masterConfigFilename, configBytes); err != nil {
+		return "", err
+	}
+
+	return dstDir, nil
+}
+
+// writeFileAtomically writes data to a temporary file in the same directory as filename, then renames it into
+// place, so that a reader never observes a partially-written master-config.yaml.
+func writeFileAtomically(filename string, data []byte) error {
+	tmpFilename := filename + ".tmp"
+	if err := ioutil.WriteFile(tmpFilename, data, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmpFilename, filename)
+}
+
+// WithBindAddress sets the API server's listen address.
+func WithBindAddress(bindAddress string) ConfigMutator {
+	return func(masterConfig *configapi.MasterConfig) error {
+		masterConfig.ServingInfo.BindAddress = bindAddress
+		return nil
+	}
+}
+
+// WithDisabledControllers disables the named controllers (given without the leading "-") on top of the default
+// controller set.
+func WithDisabledControllers(disabled ...string) ConfigMutator {
+	return func(masterConfig *configapi.MasterConfig) error {
+		controllers := []string{"*"}
+		for _, name := range disabled {
+			controllers = append(controllers, "-"+name)
+		}
+		masterConfig.ControllerConfig.Controllers = controllers
+		return nil
+	}
+}
+
+// WithFeatureGates sets the kube-apiserver's --feature-gates argument from the given gate settings.
+func WithFeatureGates(gates map[string]bool) ConfigMutator {
+	return func(masterConfig *configapi.MasterConfig) error {
+		setAPIServerArgument(masterConfig, "feature-gates", featureGateArgument(gates))
+		return nil
+	}
+}
+
+// WithAuditPolicy points the kube-apiserver at an audit policy file.
+func WithAuditPolicy(policyFile string) ConfigMutator {
+	return func(masterConfig *configapi.MasterConfig) error {
+		setAPIServerArgument(masterConfig, "audit-policy-file", policyFile)
+		return nil
+	}
+}
+
+// WithAdmissionPlugins sets the kube-apiserver's --enable-admission-plugins argument.
+func WithAdmissionPlugins(plugins ...string) ConfigMutator {
+	return func(masterConfig *configapi.MasterConfig) error {
+		setAPIServerArgument(masterConfig, "enable-admission-plugins", joinArguments(plugins))
+		return nil
+	}
+}
+
+// Apply strategic-merge-patches the MasterConfig with a user-supplied YAML patch, so cluster-up consumers can
+// layer custom master-config overrides without editing Go code.
+func Apply(patch []byte) ConfigMutator {
+	return func(masterConfig *configapi.MasterConfig) error {
+		original, err := runtime.Encode(configapilatest.Codec, masterConfig)
+		if err != nil {
+			return err
+		}
+		patchJSON, err := yaml.YAMLToJSON(patch)
+		if err != nil {
+			return err
+		}
+		patched, err := strategicpatch.StrategicMergePatch(original, patchJSON, &configapi.MasterConfig{})
+		if err != nil {
+			return err
+		}
+		patchedObj, err := runtime.Decode(configapilatest.Codec, patched)
+		if err != nil {
+			return err
+		}
+		*masterConfig = *patchedObj.(*configapi.MasterConfig)
+		return nil
+	}
+}
+
+func setAPIServerArgument(masterConfig *configapi.MasterConfig, name, value string) {
+	if masterConfig.KubernetesMasterConfig.APIServerArguments == nil {
+		masterConfig.KubernetesMasterConfig.APIServerArguments = configapi.ExtendedArguments{}
+	}
+	masterConfig.KubernetesMasterConfig.APIServerArguments[name] = []string{value}
+}
+
+// featureGateArgument renders gates as a comma-separated "name=bool" list, sorted by gate name so the generated
+// argument (and the master-config.yaml it ends up in) is stable across otherwise-identical cluster-up runs.
+func featureGateArgument(gates map[string]bool) string {
+	names := make([]string, 0, len(gates))
+	for name := range gates {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	arg := ""
+	for _, name := range names {
+		if len(arg) > 0 {
+			arg += ","
+		}
+		if gates[name] {
+			arg += name + "=true"
+		} else {
+			arg += name + "=false"
+		}
+	}
+	return arg
+}
+
+func joinArguments(values []string) string {
+	arg := ""
+	for _, value := range values {
+		if len(arg) > 0 {
+			arg += ","
+		}
+		arg += value
+	}
+	return arg
+}
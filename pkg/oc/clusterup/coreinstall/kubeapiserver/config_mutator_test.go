@@ -0,0 +1,125 @@
+package kubeapiserver
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	configapi "github.com/openshift/origin/pkg/cmd/server/apis/config"
+)
+
+func TestWithBindAddress(t *testing.T) {
+	masterConfig := &configapi.MasterConfig{}
+	if err := WithBindAddress("0.0.0.0:8444")(masterConfig); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if masterConfig.ServingInfo.BindAddress != "0.0.0.0:8444" {
+		t.Errorf("expected bind address to be set, got %q", masterConfig.ServingInfo.BindAddress)
+	}
+}
+
+func TestWithDisabledControllers(t *testing.T) {
+	masterConfig := &configapi.MasterConfig{}
+	if err := WithDisabledControllers("openshift.io/service-serving-cert", "openshift.io/build")(masterConfig); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := []string{"*", "-openshift.io/service-serving-cert", "-openshift.io/build"}
+	controllers := masterConfig.ControllerConfig.Controllers
+	if len(controllers) != len(expected) {
+		t.Fatalf("expected %v, got %v", expected, controllers)
+	}
+	for i := range expected {
+		if controllers[i] != expected[i] {
+			t.Errorf("expected %v, got %v", expected, controllers)
+			break
+		}
+	}
+}
+
+func TestWithFeatureGatesArgumentIsSorted(t *testing.T) {
+	masterConfig := &configapi.MasterConfig{}
+	gates := map[string]bool{"Zeta": true, "Alpha": false, "Mu": true}
+	if err := WithFeatureGates(gates)(masterConfig); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := "Alpha=false,Mu=true,Zeta=true"
+	got := masterConfig.KubernetesMasterConfig.APIServerArguments["feature-gates"]
+	if len(got) != 1 || got[0] != expected {
+		t.Errorf("expected feature-gates argument %q, got %v", expected, got)
+	}
+}
+
+func TestFeatureGateArgumentDeterministic(t *testing.T) {
+	gates := map[string]bool{"Zeta": true, "Alpha": false, "Mu": true}
+	first := featureGateArgument(gates)
+	for i := 0; i < 10; i++ {
+		if again := featureGateArgument(gates); again != first {
+			t.Fatalf("expected a stable argument across calls, got %q then %q", first, again)
+		}
+	}
+}
+
+func TestWithAdmissionPlugins(t *testing.T) {
+	masterConfig := &configapi.MasterConfig{}
+	if err := WithAdmissionPlugins("PodNodeSelector", "ResourceQuota")(masterConfig); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := "PodNodeSelector,ResourceQuota"
+	got := masterConfig.KubernetesMasterConfig.APIServerArguments["enable-admission-plugins"]
+	if len(got) != 1 || got[0] != expected {
+		t.Errorf("expected enable-admission-plugins argument %q, got %v", expected, got)
+	}
+}
+
+func TestWithAuditPolicy(t *testing.T) {
+	masterConfig := &configapi.MasterConfig{}
+	if err := WithAuditPolicy("/etc/origin/master/audit-policy.yaml")(masterConfig); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	got := masterConfig.KubernetesMasterConfig.APIServerArguments["audit-policy-file"]
+	if len(got) != 1 || got[0] != "/etc/origin/master/audit-policy.yaml" {
+		t.Errorf("expected audit-policy-file argument to be set, got %v", got)
+	}
+}
+
+func TestApplyRejectsMalformedPatch(t *testing.T) {
+	masterConfig := &configapi.MasterConfig{}
+	err := Apply([]byte("not valid json or yaml: ["))(masterConfig)
+	if err == nil {
+		t.Fatalf("expected an error for a malformed patch")
+	}
+}
+
+func TestWriteFileAtomicallyReplacesExistingFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "config-mutator-test")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	filename := filepath.Join(dir, "master-config.yaml")
+	if err := ioutil.WriteFile(filename, []byte("original"), 0644); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if err := writeFileAtomically(filename, []byte("replacement")); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	data, err := ioutil.ReadFile(filename)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if string(data) != "replacement" {
+		t.Errorf("expected file contents to be replaced, got %q", string(data))
+	}
+
+	if _, err := os.Stat(filename + ".tmp"); !os.IsNotExist(err) {
+		t.Errorf("expected the temporary file to be renamed away, got err=%v", err)
+	}
+}
@@ -0,0 +1,96 @@
+package kubeapiserver
+
+import (
+	"encoding/base64"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	configapi "github.com/openshift/origin/pkg/cmd/server/apis/config"
+)
+
+func TestWithEncryptionProviderGeneratesConfig(t *testing.T) {
+	dir, err := ioutil.TempDir("", "encryption-test")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	masterConfig := &configapi.MasterConfig{}
+	if err := WithEncryptionProvider(dir, "", EncryptionProviderAESCBC)(masterConfig); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	targetPath := filepath.Join(dir, encryptionConfigurationFile)
+	data, err := ioutil.ReadFile(targetPath)
+	if err != nil {
+		t.Fatalf("expected an encryption config file to be written: %v", err)
+	}
+	if !strings.Contains(string(data), "aescbc") || !strings.Contains(string(data), "identity") {
+		t.Errorf("expected the generated config to contain an aescbc provider and an identity fallback, got:\n%s", data)
+	}
+
+	got := masterConfig.KubernetesMasterConfig.APIServerArguments["encryption-provider-config"]
+	if len(got) != 1 || got[0] != targetPath {
+		t.Errorf("expected encryption-provider-config argument %q, got %v", targetPath, got)
+	}
+}
+
+func TestWithEncryptionProviderCopiesExistingConfig(t *testing.T) {
+	dir, err := ioutil.TempDir("", "encryption-test")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	existing := filepath.Join(dir, "provided-encryption-config.yaml")
+	if err := ioutil.WriteFile(existing, []byte("kind: EncryptionConfiguration\n"), 0600); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	masterConfig := &configapi.MasterConfig{}
+	if err := WithEncryptionProvider(dir, existing, EncryptionProviderAESCBC)(masterConfig); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	targetPath := filepath.Join(dir, encryptionConfigurationFile)
+	data, err := ioutil.ReadFile(targetPath)
+	if err != nil {
+		t.Fatalf("expected the provided config to be copied into the config dir: %v", err)
+	}
+	if string(data) != "kind: EncryptionConfiguration\n" {
+		t.Errorf("expected the provided config's contents to be copied verbatim, got %q", string(data))
+	}
+}
+
+func TestNewEncryptionProviderUnrecognized(t *testing.T) {
+	if _, err := newEncryptionProvider("not-a-real-provider"); err == nil {
+		t.Fatalf("expected an error for an unrecognized encryption provider")
+	}
+}
+
+func TestNewEncryptionProviderKMSUsesDefaultEndpoint(t *testing.T) {
+	provider, err := newEncryptionProvider(EncryptionProviderKMS)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if provider.KMS == nil || provider.KMS.Endpoint != defaultKMSEndpoint {
+		t.Errorf("expected the default KMS endpoint to be used, got %+v", provider.KMS)
+	}
+}
+
+func TestRandomAESKeyIs32Bytes(t *testing.T) {
+	key, err := randomAESKey()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	decoded, err := base64.StdEncoding.DecodeString(key)
+	if err != nil {
+		t.Fatalf("expected a base64-encoded key: %v", err)
+	}
+	if len(decoded) != 32 {
+		t.Errorf("expected a 32-byte key, got %d bytes", len(decoded))
+	}
+}